@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"runtime/debug"
@@ -12,13 +13,14 @@ import (
 )
 
 // Panics recovers from panics and converts the panic to an error so it is
-// reported in Metrics and handled in Errors.
+// reported and handled by the terminal error handler in web.App,
+// instead of being swallowed while the client hangs on a broken connection.
 func Panics(log *log.Logger) web.Middleware {
 
 	// This is the actual middleware function to be executed.
 	f := func(after web.Handler) web.Handler {
 
-		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) (err error) {
 			ctx, span := trace.StartSpan(ctx, "internal.middleware.Panics")
 			defer span.End()
 
@@ -30,18 +32,21 @@ func Panics(log *log.Logger) web.Middleware {
 			}
 
 			// Defer a function to recover from a panic and set the err return
-			// variable after the fact.
+			// variable after the fact. Reporting happens once this error
+			// reaches RespondError's terminal error handler, not here, so a
+			// panic isn't forwarded to the ErrorReporter twice.
 			defer func() {
-				if r := recover(); r != nil {
-					log.Printf("panic: %v", r)
-
-					// Log the Go stack trace for this panic'd goroutine.
-					log.Printf("%s :\n%s", v.TraceID, debug.Stack())
+				if rec := recover(); rec != nil {
+					stack := debug.Stack()
+					v.Stack = stack
+					err = fmt.Errorf("panic: %v\n%s", rec, stack)
+					log.Printf("%s : %v", v.TraceID, err)
 				}
 			}()
 
-			// Call the next Handler and set its return value in the err variable.
-			after(ctx, w, r)
+			// Call the next Handler and return its error so it reaches the
+			// terminal error handler.
+			return after(ctx, w, r)
 		}
 
 		return h
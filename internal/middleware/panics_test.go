@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/noelruault/go-callback-service/internal/web"
+)
+
+type countingReporter struct {
+	mu     sync.Mutex
+	calls  int
+	stacks [][]byte
+}
+
+func (cr *countingReporter) Report(ctx context.Context, err error, r *http.Request, stack []byte) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.calls++
+	cr.stacks = append(cr.stacks, stack)
+	return nil
+}
+
+func TestPanicReportedOnlyOnce(t *testing.T) {
+	reporter := &countingReporter{}
+	app := web.NewApp(web.ModeProduction, reporter, Panics(log.New(io.Discard, "", 0)))
+
+	app.Handle(http.MethodGet, "/boom", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		panic("kaboom")
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if reporter.calls != 1 {
+		t.Errorf("reporter.Report called %d times, want 1", reporter.calls)
+	}
+	if len(reporter.stacks) != 1 || len(reporter.stacks[0]) == 0 {
+		t.Errorf("stacks = %v, want exactly one non-empty stack trace", reporter.stacks)
+	}
+}
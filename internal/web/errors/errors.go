@@ -0,0 +1,112 @@
+// Package errors wraps errors with the caller's file:line and, optionally,
+// a public face and an HTTP status, so a handler can return
+// errors.Wrap(err).WithStatus(400) in one call instead of juggling separate
+// Respond/RespondError code paths.
+package errors
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Wrapped is returned by Wrap and Wrapf. It carries the caller's file:line
+// and, optionally, an HTTP status attached via WithStatus, without ever
+// exposing a Code()/Detail() pair to clients.
+type Wrapped interface {
+	error
+	Frame() string
+	Status() int
+	WithStatus(status int) Wrapped
+}
+
+// wrapped is an error annotated with the caller's file:line and,
+// optionally, an HTTP status attached via WithStatus. It implements
+// Cause() so github.com/pkg/errors.Cause can unwrap through it to whatever
+// it wraps, and it never implements PublicError, so Code()/Detail() are
+// never exposed to clients even when a status is set.
+type wrapped struct {
+	err    error
+	frame  string
+	status int
+}
+
+func (e *wrapped) Error() string { return e.err.Error() }
+func (e *wrapped) Unwrap() error { return e.err }
+func (e *wrapped) Cause() error  { return e.err }
+func (e *wrapped) Frame() string { return e.frame }
+func (e *wrapped) Status() int   { return e.status }
+
+// WithStatus attaches an HTTP status code to the error and returns it,
+// allowing a handler to return errors.Wrap(err).WithStatus(400) in one
+// call. The error stays non-public: only the status travels with it, not a
+// Code()/Detail() pair.
+func (e *wrapped) WithStatus(status int) Wrapped {
+	e.status = status
+	return e
+}
+
+// Wrap annotates err with the caller's file:line. It returns nil if err is
+// nil, so it is safe to use as `return errors.Wrap(err)`.
+func Wrap(err error) Wrapped {
+	if err == nil {
+		return nil
+	}
+	return &wrapped{err: err, frame: caller()}
+}
+
+// Wrapf annotates err with the caller's file:line and a formatted message.
+// It returns nil if err is nil.
+func Wrapf(err error, format string, args ...interface{}) Wrapped {
+	if err == nil {
+		return nil
+	}
+	msg := fmt.Sprintf(format, args...)
+	return &wrapped{err: fmt.Errorf("%s: %w", msg, err), frame: caller()}
+}
+
+// publicError is a leaf error meant to be exposed to API clients as-is. It
+// implements web.PublicError; it does not implement Cause so
+// errors.Cause stops here instead of unwrapping past the public face.
+type publicError struct {
+	code   string
+	detail string
+	status int
+	frame  string
+}
+
+// NewPublic creates an error that is safe to expose to API clients: code is
+// a short machine-readable string, detail is the human-readable message,
+// and status is the HTTP status code the caller intends to respond with.
+func NewPublic(code, detail string, status int) *publicError {
+	return &publicError{code: code, detail: detail, status: status, frame: caller()}
+}
+
+func (e *publicError) Error() string  { return fmt.Sprintf("%s: %s", e.code, e.detail) }
+func (e *publicError) Code() string   { return e.code }
+func (e *publicError) Detail() string { return e.detail }
+func (e *publicError) Status() int    { return e.status }
+func (e *publicError) Frame() string  { return e.frame }
+
+// WithStatus overrides the HTTP status attached to the error and returns it,
+// so it can be chained: errors.NewPublic(...).WithStatus(400).
+func (e *publicError) WithStatus(status int) *publicError {
+	e.status = status
+	return e
+}
+
+// IsPublic reports whether err was created with NewPublic and is therefore
+// safe to expose to API clients.
+func IsPublic(err error) bool {
+	_, ok := err.(*publicError)
+	return ok
+}
+
+// caller returns the file:line of the function that called into this
+// package, skipping this frame and the Wrap/Wrapf/NewPublic frame itself.
+func caller() string {
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestWrapNilReturnsNil(t *testing.T) {
+	if err := Wrap(nil); err != nil {
+		t.Errorf("Wrap(nil) = %v, want nil", err)
+	}
+	if err := Wrapf(nil, "context: %d", 1); err != nil {
+		t.Errorf("Wrapf(nil, ...) = %v, want nil", err)
+	}
+}
+
+type framer interface {
+	Frame() string
+}
+
+func TestWrapCapturesCallerAndUnwraps(t *testing.T) {
+	cause := errors.New("boom")
+	err := Wrap(cause)
+
+	f, ok := err.(framer)
+	if !ok {
+		t.Fatalf("Wrap(...) = %T, want something implementing Frame() string", err)
+	}
+	if !strings.Contains(f.Frame(), "errors_test.go") {
+		t.Errorf("Frame() = %q, want a errors_test.go location", f.Frame())
+	}
+	if pkgerrors.Cause(err) != cause {
+		t.Errorf("errors.Cause(err) = %v, want %v", pkgerrors.Cause(err), cause)
+	}
+}
+
+func TestWrapfFormatsMessage(t *testing.T) {
+	cause := errors.New("boom")
+	wrapped := Wrapf(cause, "while doing %s", "things")
+
+	if got := wrapped.Error(); !strings.Contains(got, "while doing things") || !strings.Contains(got, "boom") {
+		t.Errorf("Error() = %q, want it to mention both the context and the cause", got)
+	}
+}
+
+func TestNewPublicIsPublicAndCarriesStatus(t *testing.T) {
+	err := NewPublic("not_found", "missing resource", http.StatusNotFound)
+
+	if !IsPublic(err) {
+		t.Error("IsPublic(NewPublic(...)) = false, want true")
+	}
+	if err.Code() != "not_found" {
+		t.Errorf("Code() = %q, want %q", err.Code(), "not_found")
+	}
+	if err.Detail() != "missing resource" {
+		t.Errorf("Detail() = %q, want %q", err.Detail(), "missing resource")
+	}
+	if err.Status() != http.StatusNotFound {
+		t.Errorf("Status() = %d, want %d", err.Status(), http.StatusNotFound)
+	}
+}
+
+func TestWithStatusOverridesStatus(t *testing.T) {
+	err := NewPublic("bad_request", "invalid input", http.StatusBadRequest).WithStatus(http.StatusTeapot)
+
+	if err.Status() != http.StatusTeapot {
+		t.Errorf("Status() = %d, want %d", err.Status(), http.StatusTeapot)
+	}
+}
+
+func TestWrapWithStatusStaysNonPublic(t *testing.T) {
+	err := Wrap(errors.New("db connection refused")).WithStatus(http.StatusBadRequest)
+
+	if IsPublic(err) {
+		t.Error("IsPublic(Wrap(...).WithStatus(...)) = true, want false: it must not expose Code()/Detail()")
+	}
+	if err.Status() != http.StatusBadRequest {
+		t.Errorf("Status() = %d, want %d", err.Status(), http.StatusBadRequest)
+	}
+}
+
+func TestIsPublicFalseForOrdinaryErrors(t *testing.T) {
+	if IsPublic(errors.New("boom")) {
+		t.Error("IsPublic(errors.New(...)) = true, want false")
+	}
+	if IsPublic(Wrap(errors.New("boom"))) {
+		t.Error("IsPublic(Wrap(...)) = true, want false")
+	}
+}
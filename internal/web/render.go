@@ -0,0 +1,103 @@
+package web
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// ErrorRenderer renders an error response in whatever representation it
+// sees fit (JSON, HTML, ...).
+type ErrorRenderer interface {
+	Render(w http.ResponseWriter, statusCode int, body ErrorResponse) error
+}
+
+// htmlRenderer renders errors using the templates embedded under
+// templates/, selecting one by status code and falling back to a generic
+// page for anything it doesn't recognize. templates is guarded by mu since
+// RegisterErrorTemplate can run concurrently with Render once the server is
+// serving traffic.
+type htmlRenderer struct {
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+}
+
+// defaultHTMLRenderer is used by RespondError when the client prefers an
+// HTML representation. Register additional or replacement templates with
+// RegisterErrorTemplate.
+var defaultHTMLRenderer = newHTMLRenderer()
+
+func newHTMLRenderer() *htmlRenderer {
+	hr := &htmlRenderer{templates: map[string]*template.Template{}}
+
+	pages := map[string]string{
+		"404":     "templates/404.html",
+		"403":     "templates/403.html",
+		"5xx":     "templates/5xx.html",
+		"generic": "templates/generic.html",
+	}
+	for key, file := range pages {
+		hr.templates[key] = template.Must(template.ParseFS(templateFS, file))
+	}
+
+	return hr
+}
+
+// Render implements ErrorRenderer.
+func (hr *htmlRenderer) Render(w http.ResponseWriter, statusCode int, body ErrorResponse) error {
+	key := "generic"
+	switch {
+	case statusCode == http.StatusNotFound:
+		key = "404"
+	case statusCode == http.StatusForbidden:
+		key = "403"
+	case statusCode >= http.StatusInternalServerError:
+		key = "5xx"
+	}
+
+	hr.mu.RLock()
+	tmpl, ok := hr.templates[key]
+	if !ok {
+		tmpl = hr.templates["generic"]
+	}
+	hr.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(statusCode)
+	return tmpl.Execute(w, body)
+}
+
+// RegisterErrorTemplate lets consumers override or add the template used
+// for the given key ("404", "403", "5xx" or "generic").
+func RegisterErrorTemplate(key string, files ...string) error {
+	tmpl, err := template.ParseFiles(files...)
+	if err != nil {
+		return err
+	}
+	defaultHTMLRenderer.mu.Lock()
+	defaultHTMLRenderer.templates[key] = tmpl
+	defaultHTMLRenderer.mu.Unlock()
+	return nil
+}
+
+// prefersHTML reports whether the request's Accept header indicates the
+// client wants an HTML representation over the default JSON envelope. An
+// empty or absent Accept header, one that does not mention text/html (such
+// as a bare wildcard), or one that mentions application/json at all keeps
+// JSON as the default so programmatic clients are unaffected. q-values are
+// not weighed.
+func prefersHTML(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	accept := r.Header.Get("Accept")
+	if accept == "" || strings.Contains(accept, "application/json") {
+		return false
+	}
+	return strings.Contains(accept, "text/html")
+}
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/noelruault/go-callback-service/internal/web/errors"
+)
+
+func TestHandleRendersHTMLForBrowserClients(t *testing.T) {
+	app := NewApp(ModeProduction, nil)
+	app.Handle(http.MethodGet, "/missing", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return errors.NewPublic("not_found", "nope", http.StatusNotFound)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	r.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q, want text/html", ct)
+	}
+}
+
+func TestRegisterErrorTemplateConcurrentWithRender(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			w := httptest.NewRecorder()
+			defaultHTMLRenderer.Render(w, http.StatusNotFound, ErrorResponse{})
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		if err := RegisterErrorTemplate("404", "templates/404.html"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+}
@@ -0,0 +1,137 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+// ErrorReporter is notified whenever a panic is recovered or a handler
+// returns an error that is not a PublicError, whatever status RespondError
+// answers it with, so it can be forwarded to an external collector.
+// PublicErrors are not reported.
+type ErrorReporter interface {
+	Report(ctx context.Context, err error, r *http.Request, stack []byte) error
+}
+
+// LogReporter reports errors through the standard logger. It is the
+// zero-configuration default and matches the behaviour Panics had before
+// ErrorReporter was introduced.
+type LogReporter struct {
+	Log *log.Logger
+}
+
+// Report implements ErrorReporter.
+func (lr *LogReporter) Report(ctx context.Context, err error, r *http.Request, stack []byte) error {
+	if len(stack) > 0 {
+		lr.Log.Printf("error: %v\n%s", err, stack)
+		return nil
+	}
+	lr.Log.Printf("error: %v", err)
+	return nil
+}
+
+// errorPayload is the JSON body HTTPReporter posts to its webhook.
+type errorPayload struct {
+	Service   string    `json:"service"`
+	TraceID   string    `json:"trace_id"`
+	RequestID string    `json:"request_id"`
+	URL       string    `json:"url"`
+	Method    string    `json:"method"`
+	Error     string    `json:"error"`
+	Stack     string    `json:"stack"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type reportJob struct {
+	ctx   context.Context
+	err   error
+	r     *http.Request
+	stack []byte
+}
+
+// HTTPReporter posts a JSON payload describing the error to a webhook URL.
+// Report dispatches to a bounded pool of workers so a slow or unreachable
+// collector can never stall request handling.
+type HTTPReporter struct {
+	Service    string
+	WebhookURL string
+	Client     *http.Client
+
+	jobs chan reportJob
+}
+
+// NewHTTPReporter starts workers workers backed by webhookURL and returns a
+// reporter that dispatches to them.
+func NewHTTPReporter(service, webhookURL string, workers int) *HTTPReporter {
+	hr := &HTTPReporter{
+		Service:    service,
+		WebhookURL: webhookURL,
+		Client:     &http.Client{Timeout: 5 * time.Second},
+		jobs:       make(chan reportJob, workers*10),
+	}
+
+	for i := 0; i < workers; i++ {
+		go hr.worker()
+	}
+
+	return hr
+}
+
+func (hr *HTTPReporter) worker() {
+	for job := range hr.jobs {
+		hr.send(job)
+	}
+}
+
+func (hr *HTTPReporter) send(job reportJob) {
+	payload := errorPayload{
+		Service:   hr.Service,
+		Error:     job.err.Error(),
+		Stack:     string(job.stack),
+		Timestamp: time.Now().UTC(),
+	}
+	if job.r != nil {
+		payload.URL = job.r.URL.String()
+		payload.Method = job.r.Method
+	}
+	if v, ok := job.ctx.Value(KeyValues).(*Values); ok {
+		payload.RequestID = v.RequestID
+		payload.TraceID = v.TraceID
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("web: marshal error report: %v", err)
+		return
+	}
+
+	backoff := 500 * time.Millisecond
+	for attempt := 0; attempt < 3; attempt++ {
+		resp, err := hr.Client.Post(hr.WebhookURL, "application/json", bytes.NewReader(body))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < http.StatusInternalServerError {
+				return
+			}
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	log.Printf("web: failed to deliver error report to %s after retries", hr.WebhookURL)
+}
+
+// Report enqueues the error for delivery and returns immediately. If the
+// queue is full the report is dropped rather than blocking the caller.
+func (hr *HTTPReporter) Report(ctx context.Context, err error, r *http.Request, stack []byte) error {
+	select {
+	case hr.jobs <- reportJob{ctx: ctx, err: err, r: r, stack: stack}:
+	default:
+		log.Printf("web: error report queue full, dropping report")
+	}
+	return nil
+}
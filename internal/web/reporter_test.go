@@ -0,0 +1,108 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type logBuffer struct {
+	data []byte
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
+
+func (b *logBuffer) String() string { return string(b.data) }
+
+type errTest string
+
+func (e errTest) Error() string { return string(e) }
+
+func TestLogReporterLogsErrorAndStack(t *testing.T) {
+	var buf logBuffer
+	lr := &LogReporter{Log: log.New(&buf, "", 0)}
+
+	if err := lr.Report(context.Background(), errTest("boom"), nil, []byte("goroutine 1")); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "boom") || !strings.Contains(got, "goroutine 1") {
+		t.Errorf("logged output = %q, want it to contain both the error and the stack", got)
+	}
+}
+
+func TestHTTPReporterRetriesUntilSuccess(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var payload errorPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		if payload.Error != "db down" {
+			t.Errorf("payload.Error = %q, want %q", payload.Error, "db down")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	hr := NewHTTPReporter("test-service", srv.URL, 1)
+	done := make(chan struct{})
+	go func() {
+		hr.send(reportJob{ctx: context.Background(), err: errTest("db down")})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("send did not return after retries")
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3 (two failures then a success)", got)
+	}
+}
+
+func TestHTTPReporterDropsWhenQueueFull(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	defer close(block)
+
+	hr := NewHTTPReporter("test-service", srv.URL, 1)
+
+	// Fill the single worker and its queue (capacity workers*10 = 10) with
+	// jobs that can't complete until block is closed.
+	for i := 0; i < cap(hr.jobs)+1; i++ {
+		hr.Report(context.Background(), errTest("boom"), nil, nil)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		hr.Report(context.Background(), errTest("one too many"), nil, nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Report blocked instead of dropping the report on a full queue")
+	}
+}
@@ -3,8 +3,11 @@ package web
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
@@ -49,45 +52,163 @@ type PublicError interface {
 	Detail() string
 }
 
+// FieldError describes a single field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError aggregates per-field failures so a handler can return a
+// single value describing, for example, a callback payload missing
+// required fields. It implements PublicError and is rendered with HTTP 422.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return "validation failed"
+}
+
+// Code implements PublicError.
+func (e *ValidationError) Code() string {
+	return "validation_error"
+}
+
+// Detail implements PublicError.
+func (e *ValidationError) Detail() string {
+	return "one or more fields failed validation"
+}
+
 // ErrorResponse is the form used for API responses from failures in the API.
 type ErrorResponse struct {
-	Error  string `json:"error"`
-	Detail string `json:"message"`
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   string       `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	TraceID   string       `json:"trace_id,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+	Errors    []FieldError `json:"errors,omitempty"`
 }
 
 type ErrorSpan struct {
 	*trace.Span
 }
 
-// RespondError sends an error reponse back to the client.
-func RespondError(ctx context.Context, w http.ResponseWriter, err error, statusCode int) error {
-	_, span := trace.StartSpan(ctx, "web.RespondError")
+// RespondError sends an error reponse back to the client. When r's Accept
+// header prefers text/html, the error is rendered with the registered
+// ErrorRenderer instead of the default JSON envelope.
+func RespondError(ctx context.Context, w http.ResponseWriter, r *http.Request, err error, statusCode int) error {
+	ctx, span := trace.StartSpan(ctx, "web.RespondError")
 	defer span.End()
 	errSpan := ErrorSpan{span}
 
+	requestID, traceID := correlationIDs(ctx, span)
+	mode := errorMode(ctx)
+
+	er := ErrorResponse{
+		RequestID: requestID,
+		TraceID:   traceID,
+		Timestamp: time.Now().UTC(),
+	}
+	frames := callSites(err)
+	if mode == ModeDevelopment {
+		er.Details = fmt.Sprintf("%+v", err)
+		if len(frames) > 0 {
+			er.Details += "\ncall sites:\n  " + strings.Join(frames, "\n  ")
+		}
+	}
+
 	// If the error was of the type PublicError the handler has a specific
 	// status code and error message to return.
-	switch err := errors.Cause(err).(type) {
+	switch cause := errors.Cause(err).(type) {
+	case *ValidationError:
+		errSpan.zipkinSetErrorOnSpan(cause.Code(), cause.Detail(), int32(http.StatusUnprocessableEntity))
+		er.Status = http.StatusUnprocessableEntity
+		er.Code = cause.Code()
+		er.Message = cause.Detail()
+		er.Errors = cause.Errors
+		return writeError(ctx, w, r, er, http.StatusUnprocessableEntity)
 	case PublicError:
-		errSpan.zipkinSetErrorOnSpan(err.Code(), err.Detail(), int32(statusCode))
-		er := ErrorResponse{
-			Error:  err.Code(),
-			Detail: err.Detail(),
+		errSpan.zipkinSetErrorOnSpan(cause.Code(), cause.Detail(), int32(statusCode))
+		er.Status = statusCode
+		er.Code = cause.Code()
+		er.Message = cause.Detail()
+		return writeError(ctx, w, r, er, statusCode)
+	default:
+		errSpan.zipkinSetErrorOnSpan("server_error", "", int32(statusCode))
+		// In production, an unhandled error collapses to a generic body;
+		// only ModeDevelopment keeps the Details field set above.
+		if mode != ModeDevelopment {
+			er.Details = ""
+			if len(frames) > 0 {
+				log.Printf("%s : call sites: %v", traceID, frames)
+			}
 		}
-		if err := Respond(ctx, w, er, statusCode); err != nil {
-			return err
+		if v, ok := ctx.Value(KeyValues).(*Values); ok && v.Reporter != nil {
+			v.Reporter.Report(ctx, err, r, v.Stack)
 		}
-	default:
-		errSpan.zipkinSetErrorOnSpan("server_error", "", int32(http.StatusInternalServerError))
-		// If not, the handler sent any arbitrary error value so use 500.
-		if err := Respond(ctx, w,
-			ErrorResponse{Error: http.StatusText(http.StatusInternalServerError)},
-			http.StatusInternalServerError,
-		); err != nil {
-			return err
+		er.Status = statusCode
+		er.Code = "server_error"
+		er.Message = http.StatusText(statusCode)
+		return writeError(ctx, w, r, er, statusCode)
+	}
+}
+
+// writeError sends er to the client, rendering it as HTML when r's Accept
+// header prefers text/html and falling back to the default JSON envelope
+// otherwise.
+func writeError(ctx context.Context, w http.ResponseWriter, r *http.Request, er ErrorResponse, statusCode int) error {
+	if prefersHTML(r) {
+		return defaultHTMLRenderer.Render(w, statusCode, er)
+	}
+	return Respond(ctx, w, er, statusCode)
+}
+
+// errorMode reads the ErrorMode configured on the App from web.Values,
+// defaulting to ModeProduction if it is missing.
+func errorMode(ctx context.Context) ErrorMode {
+	if v, ok := ctx.Value(KeyValues).(*Values); ok {
+		return v.Mode
+	}
+	return ModeProduction
+}
+
+// framer is implemented by errors produced by the web/errors subpackage
+// (Wrap, Wrapf, NewPublic), which attach the caller's file:line.
+type framer interface {
+	Frame() string
+}
+
+// callSites walks the Unwrap chain collecting the caller file:line recorded
+// by web/errors, outermost first, so dev-mode responses and prod-mode logs
+// can show where an error was wrapped without a third-party stack library.
+func callSites(err error) []string {
+	var frames []string
+	for err != nil {
+		if f, ok := err.(framer); ok {
+			frames = append(frames, f.Frame())
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
 		}
+		err = u.Unwrap()
 	}
-	return nil
+	return frames
+}
+
+// correlationIDs pulls the request ID from web.Values and the trace ID from
+// the active OpenCensus span, so clients can correlate a failure with the
+// server logs and Zipkin traces without extra plumbing.
+func correlationIDs(ctx context.Context, span *trace.Span) (requestID, traceID string) {
+	if v, ok := ctx.Value(KeyValues).(*Values); ok {
+		requestID = v.RequestID
+	}
+	traceID = span.SpanContext().TraceID.String()
+	return requestID, traceID
 }
 
 // zipkinSetErrorOnSpan tags the span with an error code to:
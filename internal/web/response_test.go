@@ -0,0 +1,67 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRespondErrorModeControlsDetails(t *testing.T) {
+	cases := []struct {
+		name        string
+		mode        ErrorMode
+		wantDetails bool
+	}{
+		{"production hides details", ModeProduction, false},
+		{"development exposes details", ModeDevelopment, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), KeyValues, &Values{Mode: tc.mode})
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			if err := RespondError(ctx, w, r, errors.New("boom"), http.StatusInternalServerError); err != nil {
+				t.Fatalf("RespondError returned error: %v", err)
+			}
+
+			var body ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("unmarshal response: %v", err)
+			}
+
+			hasDetails := strings.Contains(body.Details, "boom")
+			if hasDetails != tc.wantDetails {
+				t.Errorf("details = %q, want present=%v", body.Details, tc.wantDetails)
+			}
+		})
+	}
+}
+
+func TestRespondErrorValidationErrorIs422(t *testing.T) {
+	ctx := context.WithValue(context.Background(), KeyValues, &Values{})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	verr := &ValidationError{Errors: []FieldError{{Field: "email", Code: "required", Message: "is required"}}}
+	if err := RespondError(ctx, w, r, verr, http.StatusBadRequest); err != nil {
+		t.Fatalf("RespondError returned error: %v", err)
+	}
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if len(body.Errors) != 1 || body.Errors[0].Field != "email" {
+		t.Errorf("errors = %+v, want one field error for email", body.Errors)
+	}
+}
@@ -0,0 +1,148 @@
+package web
+
+import (
+	"context"
+	"net/http"
+
+	"go.opencensus.io/trace"
+)
+
+// ctxKey represents the type of value for the context key.
+type ctxKey int
+
+// KeyValues is how request values are stored/retrieved from a context.Context.
+const KeyValues ctxKey = 1
+
+// ErrorMode controls how much detail RespondError and Panics expose about
+// an internal error.
+type ErrorMode int
+
+const (
+	// ModeProduction exposes only the PublicError's Code/Detail (or a
+	// generic message for internal errors) and never a stacktrace.
+	ModeProduction ErrorMode = iota
+
+	// ModeDevelopment additionally includes the wrapped error chain and
+	// the panic stacktrace in the response, to speed up local debugging.
+	ModeDevelopment
+)
+
+// Values carries information about each request as it moves through the
+// middleware chain.
+type Values struct {
+	TraceID    string
+	RequestID  string
+	StatusCode int
+	Mode       ErrorMode
+	Reporter   ErrorReporter
+
+	// Stack holds the panic stack trace recovered by the Panics middleware,
+	// if any, so RespondError can forward it to the ErrorReporter without
+	// the middleware having to report the panic itself.
+	Stack []byte
+}
+
+// Handler is the signature that all application handlers must implement.
+// Returning an error instead of writing it directly lets a single terminal
+// point turn it into a response, instead of every handler having to
+// remember to do so.
+type Handler func(ctx context.Context, w http.ResponseWriter, r *http.Request) error
+
+// Middleware is a function designed to run some code before and/or after
+// another Handler.
+type Middleware func(Handler) Handler
+
+// App is the entrypoint into the application and configures the context
+// for each of the handlers.
+type App struct {
+	mux      *http.ServeMux
+	mw       []Middleware
+	mode     ErrorMode
+	reporter ErrorReporter
+}
+
+// NewApp creates an App value that handles a set of routes for the
+// application, wrapping every handler in the given middleware. mode
+// controls how much detail error responses expose; use ModeDevelopment
+// locally and ModeProduction everywhere else. reporter receives every
+// recovered panic and every returned error that is not a PublicError; pass
+// a *LogReporter if no external sink is configured.
+func NewApp(mode ErrorMode, reporter ErrorReporter, mw ...Middleware) *App {
+	return &App{
+		mux:      http.NewServeMux(),
+		mw:       mw,
+		mode:     mode,
+		reporter: reporter,
+	}
+}
+
+// Handle associates a handler function with an HTTP method and URL pattern.
+// It is the single terminal point in the request lifecycle: if the wrapped
+// handler (and its middleware) returns an error, it is turned into a
+// response here instead of being swallowed.
+func (a *App) Handle(method, pattern string, h Handler) {
+	h = wrapMiddleware(a.mw, h)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := trace.StartSpan(r.Context(), "internal.web.App")
+		defer span.End()
+
+		ctx = context.WithValue(ctx, KeyValues, &Values{
+			TraceID:   span.SpanContext().TraceID.String(),
+			RequestID: span.SpanContext().SpanID.String(),
+			Mode:      a.mode,
+			Reporter:  a.reporter,
+		})
+
+		if err := h(ctx, w, r); err != nil {
+			RespondError(ctx, w, r, err, statusCodeOf(err))
+		}
+	}
+
+	a.mux.HandleFunc(pattern, fn)
+}
+
+// statusOf is implemented by errors that know what HTTP status they should
+// be answered with, such as the ones produced by web/errors' NewPublic and
+// WithStatus.
+type statusOf interface {
+	Status() int
+}
+
+// statusCodeOf returns the HTTP status err (or whatever it wraps) wants to
+// be answered with, or http.StatusInternalServerError if nothing in the
+// chain says. It walks the Unwrap chain outermost-first so a status set by
+// an outer WithStatus call always wins over one set further down the chain.
+func statusCodeOf(err error) int {
+	for e := err; e != nil; {
+		if se, ok := e.(statusOf); ok {
+			if status := se.Status(); status != 0 {
+				return status
+			}
+		}
+		u, ok := e.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		e = u.Unwrap()
+	}
+	return http.StatusInternalServerError
+}
+
+// ServeHTTP implements http.Handler so an App can be used directly with
+// http.ListenAndServe.
+func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// wrapMiddleware wraps a handler with the given middleware, applied in the
+// order they were provided so the first middleware ends up as the outermost
+// layer of the chain.
+func wrapMiddleware(mw []Middleware, h Handler) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		if mw[i] != nil {
+			h = mw[i](h)
+		}
+	}
+	return h
+}
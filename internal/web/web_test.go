@@ -0,0 +1,91 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/noelruault/go-callback-service/internal/web/errors"
+)
+
+func TestHandleSetsTraceAndRequestID(t *testing.T) {
+	var gotTraceID, gotRequestID string
+
+	app := NewApp(ModeProduction, nil)
+	app.Handle(http.MethodGet, "/ping", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		v, ok := ctx.Value(KeyValues).(*Values)
+		if !ok {
+			t.Fatal("web value missing from context")
+		}
+		gotTraceID = v.TraceID
+		gotRequestID = v.RequestID
+		return nil
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+
+	if gotTraceID == "" {
+		t.Error("want non-empty TraceID, got empty string")
+	}
+	if gotRequestID == "" {
+		t.Error("want non-empty RequestID, got empty string")
+	}
+}
+
+func TestHandleRespectsErrorStatus(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"plain error", fmt.Errorf("boom"), http.StatusInternalServerError},
+		{"public error with status", errors.NewPublic("not_found", "missing", http.StatusNotFound), http.StatusNotFound},
+		{"wrapped public error", errors.Wrap(errors.NewPublic("forbidden", "nope", http.StatusForbidden)), http.StatusForbidden},
+		{"wrapped non-public error with status", errors.Wrap(fmt.Errorf("db down")).WithStatus(http.StatusBadRequest), http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := NewApp(ModeProduction, nil)
+			app.Handle(http.MethodGet, "/", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+				return tc.err
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, r)
+
+			if w.Code != tc.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandleWrapWithStatusStaysNonPublic(t *testing.T) {
+	app := NewApp(ModeProduction, nil)
+	app.Handle(http.MethodGet, "/", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return errors.Wrap(fmt.Errorf("db connection refused: 10.0.0.5:5432")).WithStatus(http.StatusBadRequest)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if body.Code != "server_error" {
+		t.Errorf("Code = %q, want the generic server_error code, not the internal detail", body.Code)
+	}
+}